@@ -0,0 +1,113 @@
+package ratelimiter
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//AdaptiveRateLimiter wraps a RateLimiter and adjusts its pace based on feedback from
+//the server being called, such as HTTP 429s, Retry-After, and X-RateLimit-* headers.
+type AdaptiveRateLimiter struct {
+	*RateLimiter
+
+	baseQuota int
+	baseRate  time.Duration
+}
+
+//NewAdaptiveRateLimiter wraps limiter, remembering its configured quota/rate as the
+//baseline that OnResponse eases back toward once the server stops reporting trouble.
+func NewAdaptiveRateLimiter(limiter *RateLimiter) *AdaptiveRateLimiter {
+	return &AdaptiveRateLimiter{
+		RateLimiter: limiter,
+		baseQuota:   limiter.Quota,
+		baseRate:    limiter.Rate,
+	}
+}
+
+//OnResponse feeds back the status code and headers of a completed request so the
+//limiter can back off on exhaustion and recover toward its baseline on success.
+func (a *AdaptiveRateLimiter) OnResponse(statusCode int, headers http.Header) {
+	if statusCode == http.StatusTooManyRequests {
+		a.backOff(headers)
+		return
+	}
+	if remaining, ok := parseIntHeader(headers, "X-RateLimit-Remaining"); ok && remaining == 0 {
+		a.backOff(headers)
+		return
+	}
+	a.recover()
+}
+
+//backOff drains any buffered tokens and slows the refill rate until the server's
+//reported reset time, taken from Retry-After or X-RateLimit-Reset.
+func (a *AdaptiveRateLimiter) backOff(headers http.Header) {
+	a.setup()
+
+	wait := retryAfter(headers)
+
+	a.mu.Lock()
+	quota, rate := a.Quota, a.Rate
+	a.mu.Unlock()
+	if wait <= 0 {
+		wait = rate
+	}
+
+	for {
+		select {
+		case _, ok := <-a.Tokens:
+			if !ok {
+				return
+			}
+		default:
+			a.SetRate(quota, wait)
+			return
+		}
+	}
+}
+
+//recover halves the current wait toward the originally configured rate, one
+//OnResponse success at a time, rather than snapping back to full speed immediately.
+func (a *AdaptiveRateLimiter) recover() {
+	a.mu.Lock()
+	quota, rate := a.Quota, a.Rate
+	a.mu.Unlock()
+
+	if rate <= a.baseRate {
+		return
+	}
+	next := rate / 2
+	if next < a.baseRate {
+		next = a.baseRate
+	}
+	a.SetRate(quota, next)
+}
+
+//retryAfter parses Retry-After (delay-seconds or HTTP-date) and falls back to
+//X-RateLimit-Reset (unix timestamp), returning 0 if neither is present or parseable.
+func retryAfter(headers http.Header) time.Duration {
+	if v := headers.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+		if when, err := http.ParseTime(v); err == nil {
+			return time.Until(when)
+		}
+	}
+	if ts, ok := parseIntHeader(headers, "X-RateLimit-Reset"); ok {
+		return time.Until(time.Unix(int64(ts), 0))
+	}
+	return 0
+}
+
+func parseIntHeader(headers http.Header, name string) (int, bool) {
+	v := headers.Get(name)
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,75 @@
+package ratelimiter_test
+
+import (
+	"github.com/venkat/ratelimiter"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_AdaptiveRateLimiter_BacksOffOn429(t *testing.T) {
+	r := ratelimiter.NewRateLimiter(10, 5*time.Millisecond, "adaptive")
+	a := ratelimiter.NewAdaptiveRateLimiter(r)
+	a.Throttle()
+
+	headers := http.Header{"Retry-After": []string{"1"}}
+	a.OnResponse(http.StatusTooManyRequests, headers)
+
+	if a.Rate < time.Second {
+		t.Fatal("expected rate to slow down to at least Retry-After, got:", a.Rate)
+	}
+}
+
+func Test_AdaptiveRateLimiter_RecoversOnSuccess(t *testing.T) {
+	r := ratelimiter.NewRateLimiter(10, 5*time.Millisecond, "adaptive")
+	a := ratelimiter.NewAdaptiveRateLimiter(r)
+	a.Throttle()
+	a.OnResponse(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"1"}})
+
+	slowed := a.Rate
+	a.OnResponse(http.StatusOK, nil)
+	if a.Rate >= slowed {
+		t.Fatal("expected rate to recover toward baseline after success, still at:", a.Rate)
+	}
+	if a.Rate < 5*time.Millisecond {
+		t.Fatal("expected rate to not overshoot baseline, got:", a.Rate)
+	}
+}
+
+func Test_AdaptiveRateLimiter_SetRate_PreservesChannel(t *testing.T) {
+	r := ratelimiter.NewRateLimiter(10, 5*time.Millisecond, "setrate")
+	r.Throttle()
+	before := r.Tokens
+
+	r.SetRate(20, 10*time.Millisecond)
+
+	if r.Tokens != before {
+		t.Fatal("expected SetRate to keep the existing token channel for in-flight callers")
+	}
+	if r.Quota != 20 || r.Rate != 10*time.Millisecond {
+		t.Fatal("expected SetRate to update Quota/Rate, got:", r.Quota, r.Rate)
+	}
+}
+
+//Test_AdaptiveRateLimiter_BackOff_NoRaceWithSetRate guards against backOff reading
+//Quota/Rate without the limiter's mutex while SetRate writes them concurrently.
+func Test_AdaptiveRateLimiter_BackOff_NoRaceWithSetRate(t *testing.T) {
+	r := ratelimiter.NewRateLimiter(10, 5*time.Millisecond, "adaptive-race")
+	a := ratelimiter.NewAdaptiveRateLimiter(r)
+
+	var wg sync.WaitGroup
+	headers := http.Header{"Retry-After": []string{"1"}}
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			a.OnResponse(http.StatusTooManyRequests, headers)
+		}()
+		go func() {
+			defer wg.Done()
+			a.SetRate(10, 5*time.Millisecond)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,113 @@
+package ratelimiter
+
+import (
+	"sync"
+	"time"
+)
+
+//GCRARateLimiter implements the Generic Cell Rate Algorithm: a single "theoretical
+//arrival time" (tat) tracks when the bucket would next be empty, giving exact burst
+//semantics without the background goroutines that RateLimiter relies on.
+type GCRARateLimiter struct {
+	mu sync.Mutex
+
+	quota            int
+	period           time.Duration
+	burst            int
+	emissionInterval time.Duration
+
+	tat time.Time
+}
+
+//RateLimitResult describes the outcome of a single RateLimit call.
+type RateLimitResult struct {
+	Limited    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	ResetAt    time.Time
+}
+
+//NewGCRARateLimiter creates a GCRARateLimiter that allows quota requests per period,
+//with up to burst requests (in total, not on top of the steady rate) permitted
+//instantaneously.
+func NewGCRARateLimiter(quota int, period time.Duration, burst int) *GCRARateLimiter {
+	return &GCRARateLimiter{
+		quota:            quota,
+		period:           period,
+		burst:            burst,
+		emissionInterval: period / time.Duration(quota),
+	}
+}
+
+//RateLimit reports, without blocking, whether a request costing n tokens is allowed
+//right now. Use Throttle if you want the limiter to wait instead of deny.
+func (g *GCRARateLimiter) RateLimit(n int) (limited bool, result RateLimitResult) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	increment := time.Duration(n) * g.emissionInterval
+	burstOffset := time.Duration(g.burst) * g.emissionInterval
+
+	tat := g.tat
+	if tat.Before(now) {
+		tat = now
+	}
+
+	newTat := tat.Add(increment)
+	if newTat.Sub(now) > burstOffset {
+		return true, RateLimitResult{
+			Limited:    true,
+			Limit:      g.quota,
+			Remaining:  g.remaining(tat, now),
+			RetryAfter: newTat.Sub(now) - burstOffset,
+			ResetAt:    tat,
+		}
+	}
+
+	g.tat = newTat
+	return false, RateLimitResult{
+		Limited:   false,
+		Limit:     g.quota,
+		Remaining: g.remaining(newTat, now),
+		ResetAt:   newTat,
+	}
+}
+
+//remaining computes how many burst slots are still free given tat relative to now.
+func (g *GCRARateLimiter) remaining(tat, now time.Time) int {
+	burstOffset := time.Duration(g.burst) * g.emissionInterval
+	slack := burstOffset - tat.Sub(now)
+	if slack < 0 {
+		return 0
+	}
+	remaining := int(slack / g.emissionInterval)
+	if remaining > g.burst {
+		remaining = g.burst
+	}
+	return remaining
+}
+
+//Throttle blocks until a single token is available, matching RateLimiter's Throttle.
+func (g *GCRARateLimiter) Throttle() {
+	for {
+		limited, result := g.RateLimit(1)
+		if !limited {
+			return
+		}
+		time.Sleep(result.RetryAfter)
+	}
+}
+
+//TokensLeft reports how many requests could be made right now without being limited.
+func (g *GCRARateLimiter) TokensLeft() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := time.Now()
+	tat := g.tat
+	if tat.Before(now) {
+		tat = now
+	}
+	return g.remaining(tat, now)
+}
@@ -0,0 +1,64 @@
+package ratelimiter_test
+
+import (
+	"github.com/venkat/ratelimiter"
+	"testing"
+	"time"
+)
+
+func Test_GCRA_AllowsBurstThenDenies(t *testing.T) {
+	g := ratelimiter.NewGCRARateLimiter(10, time.Second, 3)
+
+	for i := 0; i < 3; i++ {
+		limited, result := g.RateLimit(1)
+		if limited {
+			t.Fatal("expected burst request", i, "to be allowed, result:", result)
+		}
+	}
+
+	limited, result := g.RateLimit(1)
+	if !limited {
+		t.Fatal("expected request past burst to be limited")
+	}
+	if result.RetryAfter <= 0 {
+		t.Fatal("expected positive RetryAfter, got:", result.RetryAfter)
+	}
+}
+
+func Test_GCRA_ReplenishesOverTime(t *testing.T) {
+	g := ratelimiter.NewGCRARateLimiter(10, 100*time.Millisecond, 1)
+
+	if limited, _ := g.RateLimit(1); limited {
+		t.Fatal("expected first request to be allowed")
+	}
+	limited, result := g.RateLimit(1)
+	if !limited {
+		t.Fatal("expected second immediate request to be limited")
+	}
+
+	time.Sleep(result.RetryAfter)
+	if limited, _ := g.RateLimit(1); limited {
+		t.Fatal("expected request to be allowed after waiting RetryAfter")
+	}
+}
+
+func Test_GCRA_Throttle_Blocks(t *testing.T) {
+	g := ratelimiter.NewGCRARateLimiter(10, 50*time.Millisecond, 1)
+	start := time.Now()
+	g.Throttle()
+	g.Throttle()
+	if time.Since(start) < 4*time.Millisecond {
+		t.Fatal("expected Throttle to wait for the second call")
+	}
+}
+
+func Test_GCRA_TokensLeft(t *testing.T) {
+	g := ratelimiter.NewGCRARateLimiter(10, time.Second, 5)
+	if got := g.TokensLeft(); got != 5 {
+		t.Fatal("expected 5 tokens available before any requests, got:", got)
+	}
+	g.RateLimit(1)
+	if got := g.TokensLeft(); got != 4 {
+		t.Fatal("expected 4 tokens left after one request, got:", got)
+	}
+}
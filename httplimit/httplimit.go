@@ -0,0 +1,85 @@
+/*
+   Package httplimit adapts ratelimiter's Keyed limiters into net/http middleware,
+   so the library can throttle incoming requests server-side instead of just pacing
+   outgoing calls.
+*/
+package httplimit
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/venkat/ratelimiter"
+)
+
+//VaryBy derives the rate-limit key for an incoming request. Use one of the
+//constructors below, or Custom for anything else (a user ID from context, a
+//combination of fields, ...).
+type VaryBy struct {
+	KeyFunc func(*http.Request) string
+}
+
+//RemoteIP keys by the request's remote IP, with any port stripped.
+func RemoteIP() VaryBy {
+	return VaryBy{KeyFunc: func(r *http.Request) string {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			return r.RemoteAddr
+		}
+		return host
+	}}
+}
+
+//Path keys by the request's URL path.
+func Path() VaryBy {
+	return VaryBy{KeyFunc: func(r *http.Request) string { return r.URL.Path }}
+}
+
+//Header keys by the value of the named request header.
+func Header(name string) VaryBy {
+	return VaryBy{KeyFunc: func(r *http.Request) string { return r.Header.Get(name) }}
+}
+
+//Cookie keys by the value of the named cookie, or the empty string if it's absent.
+func Cookie(name string) VaryBy {
+	return VaryBy{KeyFunc: func(r *http.Request) string {
+		c, err := r.Cookie(name)
+		if err != nil {
+			return ""
+		}
+		return c.Value
+	}}
+}
+
+//Custom keys by an arbitrary caller-supplied function.
+func Custom(keyFunc func(*http.Request) string) VaryBy {
+	return VaryBy{KeyFunc: keyFunc}
+}
+
+//Middleware returns an http middleware that enforces limiter per the key varyBy
+//derives from each request. On every request it sets X-RateLimit-Limit,
+//X-RateLimit-Remaining and X-RateLimit-Reset from the limiter's RateLimitResult; on
+//denial it additionally sets Retry-After and responds 429 Too Many Requests instead
+//of calling next.
+func Middleware(limiter ratelimiter.Keyed, varyBy VaryBy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := varyBy.KeyFunc(r)
+			limited, result := limiter.RateLimit(key, 1)
+
+			header := w.Header()
+			header.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+
+			if limited {
+				header.Set("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
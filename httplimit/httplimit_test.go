@@ -0,0 +1,62 @@
+package httplimit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/venkat/ratelimiter"
+	"github.com/venkat/ratelimiter/httplimit"
+)
+
+func Test_Middleware_AllowsThenDeniesWithHeaders(t *testing.T) {
+	limiter := ratelimiter.NewKeyedGCRALimiter(10, time.Second, 1)
+	called := false
+	handler := httplimit.Middleware(limiter, httplimit.Header("X-Client-ID"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Client-ID", "client-a")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if !called {
+		t.Fatal("expected first request to reach the handler")
+	}
+	if rec.Header().Get("X-RateLimit-Limit") != "10" {
+		t.Fatal("expected X-RateLimit-Limit to report the configured quota, got:", rec.Header().Get("X-RateLimit-Limit"))
+	}
+
+	called = false
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if called {
+		t.Fatal("expected second immediate request to be denied")
+	}
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatal("expected 429, got:", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatal("expected Retry-After header on denial")
+	}
+}
+
+func Test_Middleware_VariesByKey(t *testing.T) {
+	limiter := ratelimiter.NewKeyedGCRALimiter(10, time.Second, 1)
+	handler := httplimit.Middleware(limiter, httplimit.Header("X-Client-ID"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	reqA := httptest.NewRequest(http.MethodGet, "/", nil)
+	reqA.Header.Set("X-Client-ID", "client-a")
+	reqB := httptest.NewRequest(http.MethodGet, "/", nil)
+	reqB.Header.Set("X-Client-ID", "client-b")
+
+	handler.ServeHTTP(httptest.NewRecorder(), reqA)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, reqB)
+	if rec.Code != http.StatusOK {
+		t.Fatal("expected a different key to have its own quota, got:", rec.Code)
+	}
+}
@@ -0,0 +1,50 @@
+package ratelimiter
+
+import (
+	"sync"
+	"time"
+)
+
+//Keyed is satisfied by a rate limiter that can report, per key and without
+//blocking, whether a request is currently allowed. ratelimiter/httplimit uses it to
+//decide whether to let a request through or respond 429, and to compute the
+//X-RateLimit-* response headers from the returned RateLimitResult.
+type Keyed interface {
+	RateLimit(key string, n int) (limited bool, result RateLimitResult)
+}
+
+//KeyedGCRALimiter maintains a separate GCRARateLimiter per key, created lazily on
+//first use with a shared quota/period/burst. It is the Keyed implementation used by
+//ratelimiter/httplimit.
+type KeyedGCRALimiter struct {
+	mu       sync.Mutex
+	limiters map[string]*GCRARateLimiter
+
+	quota  int
+	period time.Duration
+	burst  int
+}
+
+//NewKeyedGCRALimiter creates a KeyedGCRALimiter whose per-key limiters all share
+//quota/period/burst.
+func NewKeyedGCRALimiter(quota int, period time.Duration, burst int) *KeyedGCRALimiter {
+	return &KeyedGCRALimiter{
+		limiters: make(map[string]*GCRARateLimiter),
+		quota:    quota,
+		period:   period,
+		burst:    burst,
+	}
+}
+
+//RateLimit reports, without blocking, whether a request costing n tokens is allowed
+//for key right now, creating key's limiter on first use.
+func (k *KeyedGCRALimiter) RateLimit(key string, n int) (limited bool, result RateLimitResult) {
+	k.mu.Lock()
+	g, ok := k.limiters[key]
+	if !ok {
+		g = NewGCRARateLimiter(k.quota, k.period, k.burst)
+		k.limiters[key] = g
+	}
+	k.mu.Unlock()
+	return g.RateLimit(n)
+}
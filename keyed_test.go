@@ -0,0 +1,21 @@
+package ratelimiter_test
+
+import (
+	"github.com/venkat/ratelimiter"
+	"testing"
+	"time"
+)
+
+func Test_KeyedGCRALimiter_PerKeyIsolation(t *testing.T) {
+	k := ratelimiter.NewKeyedGCRALimiter(10, time.Second, 1)
+
+	if limited, _ := k.RateLimit("a", 1); limited {
+		t.Fatal("expected first request for key a to be allowed")
+	}
+	if limited, _ := k.RateLimit("b", 1); limited {
+		t.Fatal("expected first request for key b to be allowed despite key a being at its burst limit")
+	}
+	if limited, _ := k.RateLimit("a", 1); !limited {
+		t.Fatal("expected second immediate request for key a to be limited")
+	}
+}
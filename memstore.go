@@ -0,0 +1,98 @@
+package ratelimiter
+
+import (
+	"sync"
+	"time"
+)
+
+//MemStore is an in-memory Store, useful for a single process or in tests. Each key
+//tracks a fixed window: the first Incr for a key starts the window, subsequent Incrs
+//within it share the same count, and the first Incr after the window elapses starts
+//a new one. A background sweep periodically prunes keys whose window has elapsed
+//and that haven't been hit again, so idle keys don't accumulate in entries forever.
+type MemStore struct {
+	mu       sync.Mutex
+	entries  map[string]*memStoreEntry
+	interval time.Duration
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+type memStoreEntry struct {
+	count      int
+	windowEnds time.Time
+}
+
+//NewMemStore creates an empty MemStore that sweeps expired keys once a minute. Call
+//Stop when the store is no longer needed to terminate the sweep goroutine.
+func NewMemStore() *MemStore {
+	return NewMemStoreWithSweepInterval(time.Minute)
+}
+
+//NewMemStoreWithSweepInterval is like NewMemStore but lets callers (and tests) pick
+//how often expired keys are pruned.
+func NewMemStoreWithSweepInterval(interval time.Duration) *MemStore {
+	m := &MemStore{
+		entries:  make(map[string]*memStoreEntry),
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+	go m.sweepLoop()
+	return m
+}
+
+func (m *MemStore) Incr(key string, window time.Duration) (count int, ttl time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	e, ok := m.entries[key]
+	if !ok || now.After(e.windowEnds) {
+		e = &memStoreEntry{windowEnds: now.Add(window)}
+		m.entries[key] = e
+	}
+	e.count++
+	return e.count, e.windowEnds.Sub(now), nil
+}
+
+func (m *MemStore) Reset(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
+
+//Stop terminates the background sweep goroutine. It is safe to call more than once.
+func (m *MemStore) Stop() {
+	m.stopOnce.Do(func() { close(m.done) })
+}
+
+//Len reports how many keys are currently tracked.
+func (m *MemStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+func (m *MemStore) sweepLoop() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.sweep()
+		case <-m.done:
+			return
+		}
+	}
+}
+
+func (m *MemStore) sweep() {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, e := range m.entries {
+		if now.After(e.windowEnds) {
+			delete(m.entries, key)
+		}
+	}
+}
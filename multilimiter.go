@@ -0,0 +1,144 @@
+package ratelimiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+//MultiLimiter maintains a separate RateLimiter per key so a single process can
+//enforce independent quotas for many callers (API tokens, IPs, tenants, ...)
+//without each caller having to maintain its own map of RateLimiters.
+type MultiLimiter struct {
+	mu       sync.RWMutex
+	limiters map[string]*multiLimiterEntry
+
+	quota int
+	rate  time.Duration
+	ttl   time.Duration
+
+	//Observer, if set, is reported MultiLimiter-level events such as OnEvict. It is
+	//not attached to the per-key RateLimiters, so their OnThrottle/OnReset events
+	//are not reported.
+	Observer Observer
+}
+
+type multiLimiterEntry struct {
+	limiter  *RateLimiter
+	lastUsed time.Time
+}
+
+//NewMultiLimiter creates a MultiLimiter whose per-key limiters default to quota/rate
+//unless overridden with Add. ttl controls how long an idle key's limiter is kept
+//around before Evict reclaims it; a ttl of 0 disables eviction.
+func NewMultiLimiter(quota int, rate time.Duration, ttl time.Duration) *MultiLimiter {
+	return &MultiLimiter{
+		limiters: make(map[string]*multiLimiterEntry),
+		quota:    quota,
+		rate:     rate,
+		ttl:      ttl,
+	}
+}
+
+//Add registers (or replaces) a per-key override so future Throttle/TakeCtx calls for
+//key use quota/rate instead of the MultiLimiter's defaults. The override lives in
+//key's limiter entry: once Evict reclaims it, the next call for key creates a
+//limiter with the defaults again.
+func (m *MultiLimiter) Add(key string, quota int, rate time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limiters[key] = &multiLimiterEntry{
+		limiter:  NewRateLimiter(quota, rate, key),
+		lastUsed: time.Now(),
+	}
+}
+
+//getOrCreate returns the limiter for key, creating one with the default quota/rate
+//on first use.
+func (m *MultiLimiter) getOrCreate(key string) *RateLimiter {
+	m.mu.RLock()
+	e, ok := m.limiters[key]
+	m.mu.RUnlock()
+	if ok {
+		m.touch(e)
+		return e.limiter
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.limiters[key]; ok {
+		e.lastUsed = time.Now()
+		return e.limiter
+	}
+	e = &multiLimiterEntry{limiter: NewRateLimiter(m.quota, m.rate, key), lastUsed: time.Now()}
+	m.limiters[key] = e
+	return e.limiter
+}
+
+func (m *MultiLimiter) touch(e *multiLimiterEntry) {
+	m.mu.Lock()
+	e.lastUsed = time.Now()
+	m.mu.Unlock()
+}
+
+//Throttle blocks until key's limiter has a token available, creating the limiter
+//with the default quota/rate if this is the first call for key. If key's limiter is
+//concurrently stopped out from under it by Evict, Throttle retries against a freshly
+//created one instead of returning early unthrottled.
+func (m *MultiLimiter) Throttle(key string) {
+	for {
+		if err := m.getOrCreate(key).Throttle(); err != ErrStopped {
+			return
+		}
+	}
+}
+
+//TakeCtx is like Throttle but returns ctx.Err() if ctx is done before a token
+//becomes available for key.
+func (m *MultiLimiter) TakeCtx(ctx context.Context, key string) error {
+	for {
+		if err := m.getOrCreate(key).TakeCtx(ctx); err != ErrStopped {
+			return err
+		}
+	}
+}
+
+//Evict removes limiters that have not been used in longer than the configured ttl,
+//stopping each one's makeTokens/resetLoop goroutines via RateLimiter.Stop so evicted
+//keys don't leak goroutines the way they would if the entry were just unmapped.
+//Call it periodically (e.g. from a time.Ticker) in long-running servers so
+//short-lived keys (closed connections, rotated tokens) don't accumulate forever.
+func (m *MultiLimiter) Evict() {
+	if m.ttl == 0 {
+		return
+	}
+	cutoff := time.Now().Add(-m.ttl)
+	m.mu.Lock()
+	var evicted []*multiLimiterEntry
+	var evictedKeys []string
+	for key, e := range m.limiters {
+		if e.lastUsed.Before(cutoff) {
+			delete(m.limiters, key)
+			evicted = append(evicted, e)
+			evictedKeys = append(evictedKeys, key)
+		}
+	}
+	observer := m.Observer
+	m.mu.Unlock()
+
+	for _, e := range evicted {
+		e.limiter.Stop()
+	}
+	if observer != nil {
+		for _, key := range evictedKeys {
+			observer.OnEvict(key)
+		}
+	}
+}
+
+//Len reports how many per-key limiters are currently tracked.
+func (m *MultiLimiter) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.limiters)
+}
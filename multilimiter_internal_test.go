@@ -0,0 +1,30 @@
+package ratelimiter
+
+import (
+	"testing"
+	"time"
+)
+
+//Test_MultiLimiter_Throttle_RetriesAfterRaceWithStop simulates Evict racing with
+//Throttle: the mapped limiter is stopped and removed out from under a concurrent
+//caller. Throttle must not swallow the resulting ErrStopped and return unthrottled;
+//it must retry against the freshly created limiter that replaces it.
+func Test_MultiLimiter_Throttle_RetriesAfterRaceWithStop(t *testing.T) {
+	m := NewMultiLimiter(1, time.Millisecond, time.Hour)
+
+	stale := m.getOrCreate("k")
+	stale.Stop()
+	m.mu.Lock()
+	delete(m.limiters, "k")
+	m.mu.Unlock()
+
+	m.Throttle("k")
+
+	fresh := m.getOrCreate("k")
+	if fresh == stale {
+		t.Fatal("expected Throttle to obtain a fresh limiter after the mapped one was stopped and evicted")
+	}
+	if got := fresh.Stats().ThrottleCount; got != 1 {
+		t.Fatal("expected the retried Throttle call to register on the fresh limiter, got ThrottleCount:", got)
+	}
+}
@@ -0,0 +1,75 @@
+package ratelimiter_test
+
+import (
+	"context"
+	"github.com/venkat/ratelimiter"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func Test_MultiLimiter_PerKeyIsolation(t *testing.T) {
+	m := ratelimiter.NewMultiLimiter(2, 50*time.Millisecond, 0)
+	m.Throttle("a")
+	m.Throttle("b")
+	if got := m.Len(); got != 2 {
+		t.Fatal("expected 2 tracked keys, got:", got)
+	}
+}
+
+func Test_MultiLimiter_Add_Override(t *testing.T) {
+	m := ratelimiter.NewMultiLimiter(1, time.Second, 0)
+	m.Add("fast", 5, time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 5; i++ {
+			m.Throttle("fast")
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("override rate was not applied, throttle took too long")
+	}
+}
+
+func Test_MultiLimiter_TakeCtx_Cancelled(t *testing.T) {
+	m := ratelimiter.NewMultiLimiter(1, time.Hour, 0)
+	m.Throttle("k") // drain the only token
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := m.TakeCtx(ctx, "k"); err != context.DeadlineExceeded {
+		t.Fatal("expected context.DeadlineExceeded, got:", err)
+	}
+}
+
+func Test_MultiLimiter_Evict(t *testing.T) {
+	m := ratelimiter.NewMultiLimiter(1, time.Millisecond, 10*time.Millisecond)
+	m.Throttle("stale")
+	time.Sleep(30 * time.Millisecond)
+	m.Evict()
+	if got := m.Len(); got != 0 {
+		t.Fatal("expected idle limiter to be evicted, got len:", got)
+	}
+}
+
+func Test_MultiLimiter_Evict_StopsLimiterGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	m := ratelimiter.NewMultiLimiter(1, time.Millisecond, 10*time.Millisecond)
+	m.Throttle("stale")
+	time.Sleep(30 * time.Millisecond)
+	m.Evict()
+
+	// give the stopped limiter's makeTokens/resetLoop goroutines a moment to exit
+	time.Sleep(20 * time.Millisecond)
+
+	if after := runtime.NumGoroutine(); after > before {
+		t.Fatal("expected evicted limiter's goroutines to exit, goroutines before:", before, "after:", after)
+	}
+}
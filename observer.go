@@ -0,0 +1,24 @@
+package ratelimiter
+
+import "time"
+
+//Observer receives lifecycle events from a RateLimiter or MultiLimiter. Set it
+//before the first call to Throttle; implementations must be safe for concurrent use,
+//since events are reported from whichever goroutine triggers them.
+type Observer interface {
+	//OnThrottle fires every time Throttle successfully hands out a token.
+	OnThrottle(name string, waited time.Duration, remaining int)
+	//OnReset fires when a limiter's window rolls over and its quota replenishes.
+	OnReset(name string)
+	//OnEvict fires when MultiLimiter.Evict reclaims an idle per-key limiter.
+	OnEvict(name string)
+}
+
+//Stats is a snapshot of a RateLimiter's cumulative counters, suitable for polling
+//from an operator's metrics endpoint.
+type Stats struct {
+	ThrottleCount int           //total number of successful Throttle calls
+	TotalWait     time.Duration //total time spent waiting for a token across all Throttle calls
+	Remaining     int           //tokens left in the current window
+	ResetAt       time.Time     //when the current window resets
+}
@@ -0,0 +1,96 @@
+package ratelimiter_test
+
+import (
+	"github.com/venkat/ratelimiter"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeObserver struct {
+	mu        sync.Mutex
+	throttles int
+	resets    int
+	evictions int
+}
+
+func (f *fakeObserver) OnThrottle(name string, waited time.Duration, remaining int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.throttles++
+}
+
+func (f *fakeObserver) OnReset(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.resets++
+}
+
+func (f *fakeObserver) OnEvict(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.evictions++
+}
+
+func (f *fakeObserver) snapshot() (throttles, resets, evictions int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.throttles, f.resets, f.evictions
+}
+
+func Test_Observer_OnThrottle(t *testing.T) {
+	obs := &fakeObserver{}
+	r := ratelimiter.NewRateLimiter(5, time.Millisecond, "observed")
+	r.Observer = obs
+
+	r.Throttle()
+	r.Throttle()
+
+	if throttles, _, _ := obs.snapshot(); throttles != 2 {
+		t.Fatal("expected 2 OnThrottle calls, got:", throttles)
+	}
+}
+
+func Test_Observer_OnReset(t *testing.T) {
+	obs := &fakeObserver{}
+	r := ratelimiter.NewRateLimiter(2, 5*time.Millisecond, "observed-reset")
+	r.Observer = obs
+
+	r.Throttle()
+	time.Sleep(30 * time.Millisecond)
+
+	if _, resets, _ := obs.snapshot(); resets == 0 {
+		t.Fatal("expected at least one OnReset call after the window elapsed")
+	}
+}
+
+func Test_Observer_OnEvict(t *testing.T) {
+	obs := &fakeObserver{}
+	m := ratelimiter.NewMultiLimiter(1, time.Millisecond, 10*time.Millisecond)
+	m.Observer = obs
+
+	m.Throttle("stale")
+	time.Sleep(30 * time.Millisecond)
+	m.Evict()
+
+	if _, _, evictions := obs.snapshot(); evictions != 1 {
+		t.Fatal("expected 1 OnEvict call, got:", evictions)
+	}
+}
+
+func Test_Stats(t *testing.T) {
+	r := ratelimiter.NewRateLimiter(5, time.Millisecond, "stats")
+	r.Throttle()
+	r.Throttle()
+
+	stats := r.Stats()
+	if stats.ThrottleCount != 2 {
+		t.Fatal("expected ThrottleCount 2, got:", stats.ThrottleCount)
+	}
+	if stats.Remaining != 3 {
+		t.Fatal("expected Remaining 3, got:", stats.Remaining)
+	}
+	if stats.ResetAt.IsZero() {
+		t.Fatal("expected a non-zero ResetAt")
+	}
+}
@@ -0,0 +1,70 @@
+/*
+   Package promobserver implements ratelimiter.Observer with Prometheus metrics, so
+   operators can see throttle counts, wait times and evictions on their existing
+   metrics endpoint instead of log lines on every call.
+*/
+package promobserver
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//Observer is a ratelimiter.Observer backed by Prometheus counters and a histogram.
+//Register it once and pass it to one or more RateLimiter/MultiLimiter's Observer
+//field; every metric is labelled by the limiter's Name.
+type Observer struct {
+	throttleTotal *prometheus.CounterVec
+	waitSeconds   *prometheus.HistogramVec
+	remaining     *prometheus.GaugeVec
+	resetTotal    *prometheus.CounterVec
+	evictTotal    *prometheus.CounterVec
+}
+
+//NewObserver creates an Observer and registers its metrics with reg.
+func NewObserver(reg prometheus.Registerer) *Observer {
+	o := &Observer{
+		throttleTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ratelimiter_throttle_total",
+			Help: "Total number of successful Throttle calls, by limiter name.",
+		}, []string{"name"}),
+		waitSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "ratelimiter_throttle_wait_seconds",
+			Help:    "Time spent waiting for a token in Throttle, by limiter name.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"name"}),
+		remaining: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "ratelimiter_tokens_remaining",
+			Help: "Tokens remaining in the current window, by limiter name.",
+		}, []string{"name"}),
+		resetTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ratelimiter_reset_total",
+			Help: "Total number of window resets, by limiter name.",
+		}, []string{"name"}),
+		evictTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ratelimiter_evict_total",
+			Help: "Total number of per-key limiters reclaimed by MultiLimiter.Evict.",
+		}, []string{"name"}),
+	}
+
+	reg.MustRegister(o.throttleTotal, o.waitSeconds, o.remaining, o.resetTotal, o.evictTotal)
+	return o
+}
+
+//OnThrottle implements ratelimiter.Observer.
+func (o *Observer) OnThrottle(name string, waited time.Duration, remaining int) {
+	o.throttleTotal.WithLabelValues(name).Inc()
+	o.waitSeconds.WithLabelValues(name).Observe(waited.Seconds())
+	o.remaining.WithLabelValues(name).Set(float64(remaining))
+}
+
+//OnReset implements ratelimiter.Observer.
+func (o *Observer) OnReset(name string) {
+	o.resetTotal.WithLabelValues(name).Inc()
+}
+
+//OnEvict implements ratelimiter.Observer.
+func (o *Observer) OnEvict(name string) {
+	o.evictTotal.WithLabelValues(name).Inc()
+}
@@ -0,0 +1,30 @@
+package promobserver_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/venkat/ratelimiter/promobserver"
+)
+
+func Test_Observer_RecordsThrottleAndReset(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	o := promobserver.NewObserver(reg)
+
+	o.OnThrottle("api", 5*time.Millisecond, 9)
+	o.OnThrottle("api", 5*time.Millisecond, 8)
+	o.OnReset("api")
+	o.OnEvict("api")
+
+	count, err := testutil.GatherAndCount(reg,
+		"ratelimiter_throttle_total", "ratelimiter_reset_total", "ratelimiter_evict_total")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Fatal("expected 3 samples across throttle/reset/evict counters, got:", count)
+	}
+}
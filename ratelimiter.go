@@ -9,13 +9,18 @@
 package ratelimiter
 
 import (
-	"log"
+	"context"
+	"errors"
+	"sync"
 	"time"
 )
 
 type nothing struct{}
 type tokenBucket chan nothing
 
+//ErrStopped is returned by Throttle once Stop has been called on its RateLimiter.
+var ErrStopped = errors.New("ratelimiter: rate limiter has been stopped")
+
 type RateLimiter struct {
 	Quota          int           //no. of hits or calls that can be made in a given window
 	Rate           time.Duration //rate at which you can hit or call something
@@ -24,46 +29,159 @@ type RateLimiter struct {
 	TokensUsed     int
 	RateLimitStart time.Time
 	Name           string
+	Observer       Observer //optional; if nil, lifecycle events are not reported
+
+	mu            sync.Mutex //guards Quota, Rate, Window, TokensUsed, RateLimitStart and stopped after setup
+	stopped       bool
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+	throttleCount int
+	totalWait     time.Duration
 }
 
 func NewRateLimiter(quota int, rate time.Duration, name string) *RateLimiter {
-	r := &RateLimiter{Quota: quota, Rate: rate, Window: time.Duration(quota) * rate, Name: name}
-	return r
+	return NewRateLimiterWithContext(context.Background(), quota, rate, name)
+}
+
+//NewRateLimiterWithContext is like NewRateLimiter but also makes Throttle and TakeCtx
+//return ErrStopped, instead of blocking indefinitely, as soon as ctx is done. Unlike
+//Stop, a cancelled ctx does not close Tokens or mark the limiter as stopped: a caller
+//reading directly from GetThrottleChannel still needs an explicit Stop to be unblocked.
+func NewRateLimiterWithContext(ctx context.Context, quota int, rate time.Duration, name string) *RateLimiter {
+	ctx, cancel := context.WithCancel(ctx)
+	return &RateLimiter{
+		Quota:  quota,
+		Rate:   rate,
+		Window: time.Duration(quota) * rate,
+		Name:   name,
+		ctx:    ctx,
+		cancel: cancel,
+	}
 }
 
 func (r *RateLimiter) setup() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return
+	}
 	if r.Tokens == nil {
 		r.Tokens = make(tokenBucket, r.Quota)
 		r.RateLimitStart = time.Now()
-		go r.makeTokens()
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.makeTokens()
+		}()
 		if r.Window != 0 {
+			r.wg.Add(1)
 			go func() {
-				for {
-					time.Sleep(r.Window)
-					r.reset()
-				}
+				defer r.wg.Done()
+				r.resetLoop()
 			}()
 		}
 	}
 }
 
-func (r *RateLimiter) useToken() {
-	<-r.Tokens
+//SetRate atomically reconfigures the limiter's quota and rate. In-flight Throttle()
+//callers are unaffected: the existing Tokens channel keeps running, only the pace at
+//which makeTokens refills it (and the window used for resets) change on their next
+//tick. The channel's buffer capacity is fixed when the limiter is first used, so a
+//new quota set after that only changes how many tokens accrue per window, not how
+//many can be buffered at once.
+func (r *RateLimiter) SetRate(quota int, rate time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Quota = quota
+	r.Rate = rate
+	r.Window = time.Duration(quota) * rate
 }
 
 //Call to Throttle should be immediately succeeded by the call to the method or function that has
-//rate limiting enforced
-func (r *RateLimiter) Throttle() {
+//rate limiting enforced. Throttle returns ErrStopped, without blocking, if Stop has
+//already been called.
+func (r *RateLimiter) Throttle() error {
+	return r.take(context.Background())
+}
+
+//TakeCtx is like Throttle but also returns ctx.Err() if ctx is done before a token
+//becomes available.
+func (r *RateLimiter) TakeCtx(ctx context.Context) error {
+	return r.take(ctx)
+}
+
+func (r *RateLimiter) take(ctx context.Context) error {
 	r.setup()
-	r.useToken()
+
+	r.mu.Lock()
+	stopped, tokens := r.stopped, r.Tokens
+	r.mu.Unlock()
+	if stopped || tokens == nil {
+		return ErrStopped
+	}
+
+	waitStart := time.Now()
+	select {
+	case _, ok := <-tokens:
+		if !ok {
+			return ErrStopped
+		}
+	case <-r.ctx.Done():
+		return ErrStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	waited := time.Since(waitStart)
+
+	r.mu.Lock()
 	r.TokensUsed++
-	log.Printf("Throttling Ratelimiter %v at %v. Rate Limit %v. Rate Limit Remaining %v. Rate Limit Resets at %v. Time Remaining %v.",
-		r.Name,
-		time.Now(),
-		r.Quota,
-		r.Quota-r.TokensUsed,
-		r.RateLimitStart.Add(r.Window),
-		r.RateLimitStart.Add(r.Window).Sub(time.Now()))
+	r.throttleCount++
+	r.totalWait += waited
+	quota, tokensUsed, observer := r.Quota, r.TokensUsed, r.Observer
+	r.mu.Unlock()
+
+	if observer != nil {
+		observer.OnThrottle(r.Name, waited, quota-tokensUsed)
+	}
+	return nil
+}
+
+//Stats returns a snapshot of this limiter's cumulative throttle count, total time
+//spent waiting for tokens, remaining quota in the current window, and when that
+//window resets.
+func (r *RateLimiter) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return Stats{
+		ThrottleCount: r.throttleCount,
+		TotalWait:     r.totalWait,
+		Remaining:     r.Quota - r.TokensUsed,
+		ResetAt:       r.RateLimitStart.Add(r.Window),
+	}
+}
+
+//Stop terminates the token-producing and window-reset goroutines and closes Tokens.
+//It is safe to call Stop more than once, and safe to call TokensLeft or Throttle
+//after stopping: Throttle returns ErrStopped instead of blocking forever on a
+//closed or nil channel.
+func (r *RateLimiter) Stop() {
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return
+	}
+	r.stopped = true
+	r.mu.Unlock()
+
+	r.cancel()
+	r.wg.Wait()
+
+	r.mu.Lock()
+	if r.Tokens != nil {
+		close(r.Tokens)
+	}
+	r.mu.Unlock()
 }
 
 //GetThrottleChannel is useful when managing multiple rate limiters that provide different
@@ -75,19 +193,52 @@ func (r *RateLimiter) GetThrottleChannel() tokenBucket {
 
 func (r *RateLimiter) makeTokens() {
 	for {
-		r.Tokens <- nothing{}
-		time.Sleep(r.Rate)
+		select {
+		case r.Tokens <- nothing{}:
+		case <-r.ctx.Done():
+			return
+		}
+
+		r.mu.Lock()
+		rate := r.Rate
+		r.mu.Unlock()
+
+		select {
+		case <-time.After(rate):
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *RateLimiter) resetLoop() {
+	for {
+		r.mu.Lock()
+		window := r.Window
+		r.mu.Unlock()
+
+		select {
+		case <-time.After(window):
+			r.reset()
+		case <-r.ctx.Done():
+			return
+		}
 	}
 }
 
 func (r *RateLimiter) reset() {
-	log.Printf("resetting ratelimiter %v at %v", r.Name, time.Now())
 	for {
 		select {
 		case <-r.Tokens:
 		default:
+			r.mu.Lock()
 			r.RateLimitStart = time.Now()
 			r.TokensUsed = 0
+			observer := r.Observer
+			r.mu.Unlock()
+			if observer != nil {
+				observer.OnReset(r.Name)
+			}
 			return
 		}
 	}
@@ -35,7 +35,7 @@ func (h *HitTracker) Hit() {
 
 func initTest(quota int, rate time.Duration) (r *ratelimiter.RateLimiter, h *HitTracker) {
 	window := time.Duration(quota) * rate
-	r = ratelimiter.NewRateLimiter(quota, rate)
+	r = ratelimiter.NewRateLimiter(quota, rate, "test")
 	h = NewHitTracker(quota, window)
 	return
 }
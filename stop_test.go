@@ -0,0 +1,60 @@
+package ratelimiter_test
+
+import (
+	"context"
+	"github.com/venkat/ratelimiter"
+	"testing"
+	"time"
+)
+
+func Test_Stop_ThrottleReturnsErrStopped(t *testing.T) {
+	r := ratelimiter.NewRateLimiter(1, time.Hour, "stoppable")
+	if err := r.Throttle(); err != nil {
+		t.Fatal("expected first Throttle to succeed, got:", err)
+	}
+
+	r.Stop()
+
+	if err := r.Throttle(); err != ratelimiter.ErrStopped {
+		t.Fatal("expected Throttle after Stop to return ErrStopped, got:", err)
+	}
+}
+
+func Test_Stop_BeforeFirstThrottle(t *testing.T) {
+	r := ratelimiter.NewRateLimiter(5, time.Millisecond, "never-started")
+	r.Stop()
+
+	if err := r.Throttle(); err != ratelimiter.ErrStopped {
+		t.Fatal("expected Throttle on a never-started, stopped limiter to return ErrStopped, got:", err)
+	}
+}
+
+func Test_Stop_TokensLeftSafeAfterStop(t *testing.T) {
+	r := ratelimiter.NewRateLimiter(5, time.Millisecond, "tokensleft")
+	r.Throttle()
+	r.Stop()
+
+	if got := r.TokensLeft(); got < 0 {
+		t.Fatal("expected TokensLeft to return a non-negative count after Stop, got:", got)
+	}
+}
+
+func Test_Stop_Idempotent(t *testing.T) {
+	r := ratelimiter.NewRateLimiter(5, time.Millisecond, "idempotent")
+	r.Throttle()
+	r.Stop()
+	r.Stop() // must not panic on double close
+}
+
+func Test_NewRateLimiterWithContext_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := ratelimiter.NewRateLimiterWithContext(ctx, 1, time.Hour, "ctx-bound")
+	r.Throttle() // drain the single token so the next call would otherwise block
+
+	cancel()
+	time.Sleep(10 * time.Millisecond) // let makeTokens/resetLoop observe ctx.Done()
+
+	if err := r.Throttle(); err != ratelimiter.ErrStopped {
+		t.Fatal("expected Throttle to return ErrStopped once ctx is cancelled, got:", err)
+	}
+}
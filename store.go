@@ -0,0 +1,49 @@
+package ratelimiter
+
+import "time"
+
+//Store tracks per-key hit counts for DistributedRateLimiter so multiple processes
+//sharing the same backing store (Redis, memcached, an in-memory map, ...) enforce a
+//single global quota instead of each process enforcing quota independently.
+type Store interface {
+	//Incr records a hit for key and returns the count within the current window,
+	//the time remaining until that window resets, and any error from the store.
+	Incr(key string, window time.Duration) (count int, ttl time.Duration, err error)
+	//Reset clears key's count, starting a fresh window on the next Incr.
+	Reset(key string)
+}
+
+//DistributedRateLimiter enforces quota hits per window for key across every process
+//sharing store, instead of each process enforcing quota independently.
+type DistributedRateLimiter struct {
+	store  Store
+	quota  int
+	window time.Duration
+	key    string
+}
+
+//NewDistributedRateLimiter creates a DistributedRateLimiter that shares its quota
+//across every process hitting store with the same key.
+func NewDistributedRateLimiter(store Store, quota int, window time.Duration, key string) *DistributedRateLimiter {
+	return &DistributedRateLimiter{store: store, quota: quota, window: window, key: key}
+}
+
+//Throttle increments the shared counter for this limiter's key and blocks out the
+//remainder of the window whenever that counter exceeds quota.
+func (d *DistributedRateLimiter) Throttle() error {
+	for {
+		count, ttl, err := d.store.Incr(d.key, d.window)
+		if err != nil {
+			return err
+		}
+		if count <= d.quota {
+			return nil
+		}
+		time.Sleep(ttl)
+	}
+}
+
+//Reset clears the shared counter for this limiter's key.
+func (d *DistributedRateLimiter) Reset() {
+	d.store.Reset(d.key)
+}
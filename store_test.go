@@ -0,0 +1,86 @@
+package ratelimiter_test
+
+import (
+	"github.com/venkat/ratelimiter"
+	"testing"
+	"time"
+)
+
+func Test_MemStore_CountsWithinWindow(t *testing.T) {
+	s := ratelimiter.NewMemStore()
+	defer s.Stop()
+
+	count, _, err := s.Incr("a", time.Minute)
+	if err != nil || count != 1 {
+		t.Fatal("expected first Incr to return count 1, got:", count, err)
+	}
+	count, _, err = s.Incr("a", time.Minute)
+	if err != nil || count != 2 {
+		t.Fatal("expected second Incr to return count 2, got:", count, err)
+	}
+}
+
+func Test_MemStore_ResetsAfterWindow(t *testing.T) {
+	s := ratelimiter.NewMemStore()
+	defer s.Stop()
+
+	s.Incr("a", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	count, _, err := s.Incr("a", 10*time.Millisecond)
+	if err != nil || count != 1 {
+		t.Fatal("expected count to restart at 1 in a new window, got:", count, err)
+	}
+}
+
+func Test_MemStore_Reset(t *testing.T) {
+	s := ratelimiter.NewMemStore()
+	defer s.Stop()
+
+	s.Incr("a", time.Minute)
+	s.Reset("a")
+
+	count, _, err := s.Incr("a", time.Minute)
+	if err != nil || count != 1 {
+		t.Fatal("expected count to restart at 1 after Reset, got:", count, err)
+	}
+}
+
+func Test_MemStore_SweepsExpiredKeys(t *testing.T) {
+	s := ratelimiter.NewMemStoreWithSweepInterval(10 * time.Millisecond)
+	defer s.Stop()
+
+	s.Incr("idle", 5*time.Millisecond)
+	if got := s.Len(); got != 1 {
+		t.Fatal("expected 1 tracked key right after Incr, got:", got)
+	}
+
+	time.Sleep(40 * time.Millisecond) // let the key's window lapse and a sweep run
+
+	if got := s.Len(); got != 0 {
+		t.Fatal("expected the expired, unused key to be swept away, got len:", got)
+	}
+}
+
+func Test_DistributedRateLimiter_ThrottlesAcrossSharedStore(t *testing.T) {
+	store := ratelimiter.NewMemStore()
+	defer store.Stop()
+
+	a := ratelimiter.NewDistributedRateLimiter(store, 2, 50*time.Millisecond, "shared")
+	b := ratelimiter.NewDistributedRateLimiter(store, 2, 50*time.Millisecond, "shared")
+
+	if err := a.Throttle(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Throttle(); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if err := a.Throttle(); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) < 10*time.Millisecond {
+		t.Fatal("expected third hit against the shared quota to wait for the window")
+	}
+}